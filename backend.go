@@ -0,0 +1,37 @@
+package work
+
+import "context"
+
+// Doc is a single decoded record returned by a Backend. It mirrors the
+// firestore.DocumentSnapshot.DataTo signature so the Firestore backend can
+// satisfy it without any wrapping.
+type Doc interface {
+	DataTo(dest any) error
+}
+
+// Backend is the storage abstraction every Storage* generic helper
+// delegates to. Projects register a backend at startup with UseBackend;
+// NewFireStore does this automatically, so a Firestore deployment needs no
+// separate UseBackend call. Implementing a Backend (e.g. Postgres, or MemoryBackend for
+// tests) lets handler code stay the same while swapping where the data
+// actually lives.
+type Backend interface {
+	NewId(ctx context.Context, collection string) string
+	Get(ctx context.Context, collection string, id string) (Doc, error)
+	List(ctx context.Context, collection string, payload Payload) (int, []Doc, error)
+	Sync(ctx context.Context, collection string, id string, entity any) error
+	SyncList(ctx context.Context, collection string, filters []Filter, field string, value string) error
+	Delete(ctx context.Context, collection string, id string) error
+	Count(ctx context.Context, collection string, filters []Filter) (int, error)
+	Sum(ctx context.Context, collection string, filters []Filter, field string) (float64, error)
+}
+
+var backend Backend
+
+// UseBackend registers the backend used by the Storage* helpers. Call it
+// once at startup before serving any requests, e.g.:
+//
+//	work.UseBackend(work.NewMemoryBackend())
+func UseBackend(b Backend) {
+	backend = b
+}
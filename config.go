@@ -0,0 +1,231 @@
+package work
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config holds the typed settings NewServer, NewValidator, and NewFireStore
+// all consume, replacing the three places that used to each independently
+// open ./config.json and type-assert its way into a map[string]interface{}.
+type Config struct {
+	Port             int
+	SecretKey        string
+	AllowedHosts     []string
+	ProjectId        string
+	TranslationsPath string
+	AccessTTLMinutes float64
+	RefreshTTLHours  float64
+
+	// Server timeouts, all in seconds. RequestTimeoutSeconds bounds how
+	// long a single request's context (and so any Storage* calls it
+	// makes) stays alive; the others configure the underlying
+	// *http.Server.
+	ReadTimeoutSeconds    float64
+	WriteTimeoutSeconds   float64
+	IdleTimeoutSeconds    float64
+	RequestTimeoutSeconds float64
+
+	path string // the config.json path LoadConfig read, for WatchConfig
+	raw  []byte // the raw config.json bytes, reused as Firestore credentials JSON
+}
+
+// LoadConfig reads the first of paths that exists (defaulting to
+// ./config.json), overlays WORK_*-prefixed environment variables on top of
+// it, and validates the fields NewServer needs. Env vars always win over
+// the file, so a deployment can keep a checked-in config.json and override
+// secrets per-environment.
+func LoadConfig(paths ...string) (*Config, error) {
+	if len(paths) == 0 {
+		paths = []string{"./config.json"}
+	}
+
+	var raw []byte
+	var path string
+	for _, p := range paths {
+		data, err := os.ReadFile(p)
+		if err != nil {
+			continue
+		}
+		raw, path = data, p
+		break
+	}
+
+	if raw == nil {
+		return nil, fmt.Errorf("work: no config file found in %v", paths)
+	}
+
+	var values map[string]interface{}
+	if err := json.Unmarshal(raw, &values); err != nil {
+		return nil, fmt.Errorf("work: malformed config %s: %w", path, err)
+	}
+
+	cfg := &Config{
+		Port:             overlayInt(intField(values, "server_port"), "WORK_SERVER_PORT"),
+		SecretKey:        overlayString(stringField(values, "server_secret_key"), "WORK_SERVER_SECRET_KEY"),
+		AllowedHosts:     overlayStringSlice(stringSliceField(values, "server_allowed_hosts"), "WORK_SERVER_ALLOWED_HOSTS"),
+		ProjectId:        overlayString(stringField(values, "project_id"), "WORK_PROJECT_ID"),
+		TranslationsPath: overlayString(stringFieldOr(values, "translations_path", "./translations.json"), "WORK_TRANSLATIONS_PATH"),
+		AccessTTLMinutes: overlayFloat(floatFieldOr(values, "server_access_ttl_minutes", 24*60), "WORK_SERVER_ACCESS_TTL_MINUTES"),
+		RefreshTTLHours:  overlayFloat(floatFieldOr(values, "server_refresh_ttl_hours", 30*24), "WORK_SERVER_REFRESH_TTL_HOURS"),
+
+		ReadTimeoutSeconds:    overlayFloat(floatFieldOr(values, "server_read_timeout_seconds", 15), "WORK_SERVER_READ_TIMEOUT_SECONDS"),
+		WriteTimeoutSeconds:   overlayFloat(floatFieldOr(values, "server_write_timeout_seconds", 15), "WORK_SERVER_WRITE_TIMEOUT_SECONDS"),
+		IdleTimeoutSeconds:    overlayFloat(floatFieldOr(values, "server_idle_timeout_seconds", 60), "WORK_SERVER_IDLE_TIMEOUT_SECONDS"),
+		RequestTimeoutSeconds: overlayFloat(floatFieldOr(values, "server_request_timeout_seconds", 10), "WORK_SERVER_REQUEST_TIMEOUT_SECONDS"),
+
+		path: path,
+		raw:  raw,
+	}
+
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+func (c *Config) validate() error {
+	var missing []string
+
+	if c.Port == 0 {
+		missing = append(missing, "server_port")
+	}
+	if c.SecretKey == "" {
+		missing = append(missing, "server_secret_key")
+	}
+	if len(c.AllowedHosts) == 0 {
+		missing = append(missing, "server_allowed_hosts")
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("work: missing required config fields: %s", strings.Join(missing, ", "))
+	}
+
+	return nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////
+// field extraction + env overlay
+
+func intField(values map[string]interface{}, key string) int {
+	f, _ := values[key].(float64)
+	return int(f)
+}
+
+func floatFieldOr(values map[string]interface{}, key string, def float64) float64 {
+	f, ok := values[key].(float64)
+	if !ok {
+		return def
+	}
+	return f
+}
+
+func stringField(values map[string]interface{}, key string) string {
+	s, _ := values[key].(string)
+	return s
+}
+
+func stringFieldOr(values map[string]interface{}, key string, def string) string {
+	s, ok := values[key].(string)
+	if !ok || s == "" {
+		return def
+	}
+	return s
+}
+
+func stringSliceField(values map[string]interface{}, key string) []string {
+	s, ok := values[key].(string)
+	if !ok || s == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
+
+func overlayString(value string, envKey string) string {
+	if v := os.Getenv(envKey); v != "" {
+		return v
+	}
+	return value
+}
+
+func overlayInt(value int, envKey string) int {
+	if v := os.Getenv(envKey); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			return n
+		}
+	}
+	return value
+}
+
+func overlayFloat(value float64, envKey string) float64 {
+	if v := os.Getenv(envKey); v != "" {
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return f
+		}
+	}
+	return value
+}
+
+func overlayStringSlice(value []string, envKey string) []string {
+	if v := os.Getenv(envKey); v != "" {
+		return strings.Split(v, ",")
+	}
+	return value
+}
+
+// ////////////////////////////////////////////////////////////////////////////
+// hot reload
+
+// WatchConfig polls cfg's source file every interval and, when it changes,
+// re-reads AllowedHosts and TranslationsPath into the running server -
+// other fields (port, secret key, TTLs) still require a restart. Stop it
+// by closing stop.
+func WatchConfig(cfg *Config, interval time.Duration, stop <-chan struct{}) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		lastMod := modTime(cfg.path)
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				mod := modTime(cfg.path)
+				if mod.Equal(lastMod) {
+					continue
+				}
+				lastMod = mod
+
+				reloaded, err := LoadConfig(cfg.path)
+				if err != nil {
+					logger.Warn("config reload failed", "path", cfg.path, "error", err)
+					continue
+				}
+
+				setAllowedHosts(reloaded.AllowedHosts)
+
+				if err := loadTranslations(reloaded.TranslationsPath); err != nil {
+					logger.Warn("translations reload failed", "path", reloaded.TranslationsPath, "error", err)
+					continue
+				}
+
+				logger.Info("config reloaded", "path", cfg.path)
+			}
+		}
+	}()
+}
+
+func modTime(path string) time.Time {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
@@ -0,0 +1,114 @@
+package work
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeConfig(t *testing.T, dir, name, contents string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatalf("writing test config: %v", err)
+	}
+	return path
+}
+
+func TestLoadConfigMissingFile(t *testing.T) {
+	if _, err := LoadConfig(filepath.Join(t.TempDir(), "does-not-exist.json")); err == nil {
+		t.Fatal("LoadConfig should error when no path exists")
+	}
+}
+
+func TestLoadConfigMalformedJSON(t *testing.T) {
+	path := writeConfig(t, t.TempDir(), "config.json", "{not json")
+
+	if _, err := LoadConfig(path); err == nil {
+		t.Fatal("LoadConfig should error on malformed JSON")
+	}
+}
+
+func TestLoadConfigMissingRequiredFields(t *testing.T) {
+	path := writeConfig(t, t.TempDir(), "config.json", "{}")
+
+	_, err := LoadConfig(path)
+	if err == nil {
+		t.Fatal("LoadConfig should error when required fields are missing")
+	}
+
+	for _, field := range []string{"server_port", "server_secret_key", "server_allowed_hosts"} {
+		if !strings.Contains(err.Error(), field) {
+			t.Errorf("error %q should mention missing field %q", err.Error(), field)
+		}
+	}
+}
+
+func TestLoadConfigMultiPathFallback(t *testing.T) {
+	dir := t.TempDir()
+	path := writeConfig(t, dir, "config.json", `{
+		"server_port": 8080,
+		"server_secret_key": "secret",
+		"server_allowed_hosts": "https://example.com"
+	}`)
+
+	cfg, err := LoadConfig(filepath.Join(dir, "missing.json"), path)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if cfg.Port != 8080 {
+		t.Fatalf("Port = %d, want 8080", cfg.Port)
+	}
+}
+
+func TestLoadConfigEnvOverridesFile(t *testing.T) {
+	path := writeConfig(t, t.TempDir(), "config.json", `{
+		"server_port": 8080,
+		"server_secret_key": "file-secret",
+		"server_allowed_hosts": "https://example.com"
+	}`)
+
+	t.Setenv("WORK_SERVER_PORT", "9090")
+	t.Setenv("WORK_SERVER_SECRET_KEY", "env-secret")
+
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if cfg.Port != 9090 {
+		t.Fatalf("Port = %d, want 9090 (env should win over file)", cfg.Port)
+	}
+	if cfg.SecretKey != "env-secret" {
+		t.Fatalf("SecretKey = %q, want env-secret", cfg.SecretKey)
+	}
+}
+
+func TestLoadConfigDefaultsAndEnvSlice(t *testing.T) {
+	path := writeConfig(t, t.TempDir(), "config.json", `{
+		"server_port": 8080,
+		"server_secret_key": "secret",
+		"server_allowed_hosts": "https://example.com"
+	}`)
+
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if cfg.ReadTimeoutSeconds != 15 {
+		t.Fatalf("ReadTimeoutSeconds = %v, want default 15", cfg.ReadTimeoutSeconds)
+	}
+	if cfg.TranslationsPath != "./translations.json" {
+		t.Fatalf("TranslationsPath = %q, want default ./translations.json", cfg.TranslationsPath)
+	}
+
+	t.Setenv("WORK_SERVER_ALLOWED_HOSTS", "https://a.com,https://b.com")
+
+	cfg, err = LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if len(cfg.AllowedHosts) != 2 || cfg.AllowedHosts[0] != "https://a.com" || cfg.AllowedHosts[1] != "https://b.com" {
+		t.Fatalf("AllowedHosts = %v, want env-overridden [https://a.com https://b.com]", cfg.AllowedHosts)
+	}
+}
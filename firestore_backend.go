@@ -0,0 +1,258 @@
+package work
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"cloud.google.com/go/firestore/apiv1/firestorepb"
+	"google.golang.org/api/option"
+)
+
+var storage *firestore.Client
+
+// NewFireStore opens the Firestore client using cfg's ProjectId and raw
+// config bytes as credentials JSON, and registers it as the active
+// Backend. Call UseBackend afterwards if you want to override it (e.g. in
+// tests).
+func NewFireStore(cfg *Config) error {
+	if cfg.ProjectId == "" {
+		return fmt.Errorf("work: config is missing project_id")
+	}
+
+	client, err := firestore.NewClient(context.Background(), cfg.ProjectId, option.WithCredentialsJSON(cfg.raw))
+	if err != nil {
+		return err
+	}
+
+	storage = client
+	UseBackend(&firestoreBackend{})
+
+	return nil
+}
+
+func FireStoreClose() {
+	if storage != nil {
+		storage.Close()
+	}
+}
+
+// firestoreBackend is the default Backend, delegating to the package-level
+// Firestore client set up by NewFireStore.
+type firestoreBackend struct{}
+
+func (b *firestoreBackend) NewId(ctx context.Context, collection string) string {
+	return storage.Collection(collection).NewDoc().ID
+}
+
+func (b *firestoreBackend) Get(ctx context.Context, collection string, id string) (Doc, error) {
+	return storage.Collection(collection).Doc(id).Get(ctx)
+}
+
+func (b *firestoreBackend) List(ctx context.Context, collection string, payload Payload) (int, []Doc, error) {
+	coll := storage.Collection(collection)
+
+	if payload.Search != "" {
+		return b.searchList(ctx, coll, payload)
+	}
+
+	query := filter(coll.Query, payload.Filters)
+
+	total, err := count(ctx, query)
+	if err != nil {
+		return 0, nil, err
+	}
+	if total == 0 {
+		return 0, nil, nil
+	}
+
+	query = order(query, payload.Orders)
+
+	if payload.PageSize > 0 {
+		query = query.Limit(payload.PageSize)
+	}
+
+	if payload.Page > 0 {
+		query = query.Offset(payload.Page * payload.PageSize)
+	}
+
+	snap, err := query.Documents(ctx).GetAll()
+	if err != nil {
+		return total, nil, err
+	}
+
+	docs := make([]Doc, len(snap))
+	for i, doc := range snap {
+		docs[i] = doc
+	}
+
+	return total, docs, nil
+}
+
+// searchList handles Payload.Search. Firestore only allows one
+// array-contains filter per query, so the first search token is pushed
+// down to prune via the Raw index and the rest are AND-ed client-side
+// against each candidate's Raw field. That means Count/paging for a
+// search query materialize every document matching the first token
+// instead of letting Firestore page server-side - fine for the realistic
+// size of a trigram index, but worth knowing if a query's first token is
+// very common.
+func (b *firestoreBackend) searchList(ctx context.Context, coll *firestore.CollectionRef, payload Payload) (int, []Doc, error) {
+	tokens := searchTokens(payload.Search)
+	if len(tokens) == 0 {
+		return 0, nil, nil
+	}
+
+	filters := append(append([]Filter{}, payload.Filters...), Filter{Field: RAW, Operator: ARRAYCONTAINS, Value: tokens[0]})
+	query := order(filter(coll.Query, filters), payload.Orders)
+
+	snap, err := query.Documents(ctx).GetAll()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var docs []Doc
+	for _, doc := range snap {
+		if matchesAllTokens(toStringSlice(doc.Data()[RAW]), tokens[1:]) {
+			docs = append(docs, doc)
+		}
+	}
+
+	total := len(docs)
+
+	if payload.PageSize > 0 {
+		start := payload.Page * payload.PageSize
+		if start > len(docs) {
+			start = len(docs)
+		}
+		end := start + payload.PageSize
+		if end > len(docs) {
+			end = len(docs)
+		}
+		docs = docs[start:end]
+	}
+
+	return total, docs, nil
+}
+
+func (b *firestoreBackend) Sync(ctx context.Context, collection string, id string, entity any) error {
+	_, err := storage.Collection(collection).Doc(id).Set(ctx, entity)
+	return err
+}
+
+func (b *firestoreBackend) SyncList(ctx context.Context, collection string, filters []Filter, field string, value string) error {
+	coll := storage.Collection(collection)
+	query := filter(coll.Query, filters)
+
+	snap, _ := query.Documents(ctx).GetAll()
+	if len(snap) == 0 {
+		return nil
+	}
+
+	batch := storage.Batch()
+	for _, doc := range snap {
+		batch.Set(doc.Ref, map[string]interface{}{
+			field: value,
+		}, firestore.MergeAll)
+	}
+
+	_, err := batch.Commit(ctx)
+	if err != nil {
+		logger.Error("firestore sync list commit failed", "collection", collection, "field", field, "error", err)
+	}
+
+	return err
+}
+
+func (b *firestoreBackend) Delete(ctx context.Context, collection string, id string) error {
+	_, err := storage.Collection(collection).Doc(id).Delete(ctx)
+	return err
+}
+
+func (b *firestoreBackend) Count(ctx context.Context, collection string, filters []Filter) (int, error) {
+	coll := storage.Collection(collection)
+	query := filter(coll.Query, filters)
+
+	return count(ctx, query)
+}
+
+func (b *firestoreBackend) Sum(ctx context.Context, collection string, filters []Filter, field string) (float64, error) {
+	coll := storage.Collection(collection)
+	query := filter(coll.Query, filters)
+
+	snap, _ := query.Documents(ctx).GetAll()
+	if len(snap) == 0 {
+		return 0, nil
+	}
+
+	sum := float64(0)
+	for _, doc := range snap {
+		v := doc.Data()[field]
+		str := fmt.Sprintf("%v", v)
+		v2, err := strconv.ParseFloat(str, 64)
+		if err == nil {
+			sum += v2
+		}
+	}
+
+	return sum, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////
+// util
+
+func count(ctx context.Context, query firestore.Query) (int, error) {
+	q := query.NewAggregationQuery().WithCount("count")
+
+	r, err := q.Get(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	i, ok := r["count"].(*firestorepb.Value)
+	if !ok {
+		return 0, fmt.Errorf("work: unexpected aggregation result %v", r["count"])
+	}
+
+	return int(i.GetIntegerValue()), nil
+}
+
+func filter(query firestore.Query, filters []Filter) firestore.Query {
+	for _, f := range filters {
+		switch f.Type {
+		case BOOLEAN:
+			value, err := strconv.ParseBool(f.Value)
+			if err == nil {
+				query = query.Where(f.Field, string(f.Operator), value)
+			}
+		case NUMBER:
+			value, err := strconv.ParseFloat(f.Value, 64)
+			if err == nil {
+				query = query.Where(f.Field, string(f.Operator), value)
+			}
+		case DATE:
+			value, err := time.Parse("2006-01-02", f.Value)
+			if err == nil {
+				query = query.Where(f.Field, string(f.Operator), value)
+			}
+		default:
+			query = query.Where(f.Field, string(f.Operator), f.Value)
+		}
+	}
+
+	return query
+}
+
+func order(query firestore.Query, orders []Order) firestore.Query {
+	for _, o := range orders {
+		sort := firestore.Asc
+		if o.Descending {
+			sort = firestore.Desc
+		}
+		query = query.OrderBy(o.Field, sort)
+	}
+
+	return query
+}
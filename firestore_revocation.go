@@ -0,0 +1,47 @@
+package work
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const revocationCollection Collection = "revoked_tokens"
+
+// FirestoreRevocationStore is a RevocationStore backed by Firestore, for
+// deployments with more than one server instance where a MemoryRevocationStore
+// wouldn't be shared.
+type FirestoreRevocationStore struct{}
+
+func NewFirestoreRevocationStore() *FirestoreRevocationStore {
+	return &FirestoreRevocationStore{}
+}
+
+func (s *FirestoreRevocationStore) Revoke(ctx context.Context, jti string, expiresAt time.Time) error {
+	_, err := storage.Collection(string(revocationCollection)).Doc(jti).Set(ctx, map[string]interface{}{
+		"ExpiresAt": expiresAt,
+	})
+
+	return err
+}
+
+func (s *FirestoreRevocationStore) IsRevoked(ctx context.Context, jti string) (bool, error) {
+	doc, err := storage.Collection(string(revocationCollection)).Doc(jti).Get(ctx)
+	if status.Code(err) == codes.NotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	var data struct {
+		ExpiresAt time.Time
+	}
+	if err := doc.DataTo(&data); err != nil {
+		return false, err
+	}
+
+	return time.Now().Before(data.ExpiresAt), nil
+}
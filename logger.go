@@ -0,0 +1,40 @@
+package work
+
+import (
+	"log/slog"
+	"os"
+)
+
+// Logger is the structured logging interface used throughout the package,
+// replacing the old log.Println/log.Fatal calls. Call SetLogger to route
+// logs through the host application's own sink instead of the default
+// slog-backed one.
+type Logger interface {
+	Debug(msg string, fields ...any)
+	Info(msg string, fields ...any)
+	Warn(msg string, fields ...any)
+	Error(msg string, fields ...any)
+}
+
+var logger Logger = newSlogLogger()
+
+// SetLogger overrides the package-level Logger. Call it once at startup,
+// before serving any requests.
+func SetLogger(l Logger) {
+	logger = l
+}
+
+// slogLogger is the default Logger, backed by the standard library's
+// structured logger.
+type slogLogger struct {
+	base *slog.Logger
+}
+
+func newSlogLogger() *slogLogger {
+	return &slogLogger{base: slog.New(slog.NewJSONHandler(os.Stderr, nil))}
+}
+
+func (l *slogLogger) Debug(msg string, fields ...any) { l.base.Debug(msg, fields...) }
+func (l *slogLogger) Info(msg string, fields ...any)  { l.base.Info(msg, fields...) }
+func (l *slogLogger) Warn(msg string, fields ...any)  { l.base.Warn(msg, fields...) }
+func (l *slogLogger) Error(msg string, fields ...any) { l.base.Error(msg, fields...) }
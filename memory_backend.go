@@ -0,0 +1,271 @@
+package work
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// MemoryBackend is a Backend implementation that keeps everything in
+// process memory. It exists so handler code can be unit tested without a
+// live Firestore instance:
+//
+//	work.UseBackend(work.NewMemoryBackend())
+//
+// It supports the same Filter/Order semantics as the Firestore backend for
+// equality, "!=" and array-contains filters, but doesn't enforce Firestore's
+// index requirements. The range operators (<, <=, >, >=) are applied only to
+// NUMBER filters; on other filter types they match by equality. Orders
+// compare field values as their %v strings, so numbers sort lexically.
+type MemoryBackend struct {
+	mu      sync.RWMutex
+	data    map[string]map[string]map[string]interface{}
+	idCount uint64
+}
+
+func NewMemoryBackend() *MemoryBackend {
+	return &MemoryBackend{data: map[string]map[string]map[string]interface{}{}}
+}
+
+func (b *MemoryBackend) NewId(ctx context.Context, collection string) string {
+	id := atomic.AddUint64(&b.idCount, 1)
+	return fmt.Sprintf("%s-%d", collection, id)
+}
+
+// collection returns (lazily creating) the named collection's map. It
+// mutates b.data, so callers must hold b.mu for writing.
+func (b *MemoryBackend) collection(name string) map[string]map[string]interface{} {
+	if b.data[name] == nil {
+		b.data[name] = map[string]map[string]interface{}{}
+	}
+	return b.data[name]
+}
+
+// readCollection returns the named collection's map without creating it, so
+// it's safe to call while only holding b.mu for reading - indexing/ranging a
+// nil map behaves like an empty one.
+func (b *MemoryBackend) readCollection(name string) map[string]map[string]interface{} {
+	return b.data[name]
+}
+
+type memoryDoc struct {
+	data map[string]interface{}
+}
+
+func (d *memoryDoc) DataTo(dest any) error {
+	raw, err := json.Marshal(d.data)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, dest)
+}
+
+func (b *MemoryBackend) Get(ctx context.Context, collection string, id string) (Doc, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	doc, ok := b.readCollection(collection)[id]
+	if !ok {
+		return nil, fmt.Errorf("memory backend: %s/%s not found", collection, id)
+	}
+
+	return &memoryDoc{doc}, nil
+}
+
+func (b *MemoryBackend) List(ctx context.Context, collection string, payload Payload) (int, []Doc, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	tokens := searchTokens(payload.Search)
+
+	var docs []map[string]interface{}
+	for _, doc := range b.readCollection(collection) {
+		if !matchesFilters(doc, payload.Filters) {
+			continue
+		}
+		if len(tokens) > 0 && !matchesAllTokens(toStringSlice(doc[RAW]), tokens) {
+			continue
+		}
+		docs = append(docs, doc)
+	}
+
+	sortDocs(docs, payload.Orders)
+	total := len(docs)
+
+	if payload.PageSize > 0 {
+		start := payload.Page * payload.PageSize
+		if start > len(docs) {
+			start = len(docs)
+		}
+		end := start + payload.PageSize
+		if end > len(docs) {
+			end = len(docs)
+		}
+		docs = docs[start:end]
+	}
+
+	result := make([]Doc, len(docs))
+	for i, doc := range docs {
+		result[i] = &memoryDoc{doc}
+	}
+
+	return total, result, nil
+}
+
+func (b *MemoryBackend) Sync(ctx context.Context, collection string, id string, entity any) error {
+	raw, err := json.Marshal(entity)
+	if err != nil {
+		return err
+	}
+
+	var data map[string]interface{}
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return err
+	}
+
+	// Raw is tagged json:"-" (Firestore's own client serializes it by
+	// field name instead), so the JSON round-trip above drops it. Pull it
+	// back out via reflection so search still works against this backend.
+	if field := reflect.ValueOf(entity).FieldByName(RAW); field.IsValid() {
+		data[RAW] = field.Interface()
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.collection(collection)[id] = data
+
+	return nil
+}
+
+func (b *MemoryBackend) SyncList(ctx context.Context, collection string, filters []Filter, field string, value string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, doc := range b.collection(collection) {
+		if matchesFilters(doc, filters) {
+			doc[field] = value
+		}
+	}
+
+	return nil
+}
+
+func (b *MemoryBackend) Delete(ctx context.Context, collection string, id string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	delete(b.collection(collection), id)
+	return nil
+}
+
+func (b *MemoryBackend) Count(ctx context.Context, collection string, filters []Filter) (int, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	n := 0
+	for _, doc := range b.readCollection(collection) {
+		if matchesFilters(doc, filters) {
+			n++
+		}
+	}
+
+	return n, nil
+}
+
+func (b *MemoryBackend) Sum(ctx context.Context, collection string, filters []Filter, field string) (float64, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	sum := 0.0
+	for _, doc := range b.readCollection(collection) {
+		if !matchesFilters(doc, filters) {
+			continue
+		}
+
+		str := fmt.Sprintf("%v", doc[field])
+		if v, err := strconv.ParseFloat(str, 64); err == nil {
+			sum += v
+		}
+	}
+
+	return sum, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////
+// util
+
+func matchesFilters(doc map[string]interface{}, filters []Filter) bool {
+	for _, f := range filters {
+		if !matchesFilter(doc[f.Field], f) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchesFilter(value interface{}, f Filter) bool {
+	if f.Type == NUMBER {
+		want, errWant := strconv.ParseFloat(f.Value, 64)
+		got, errGot := strconv.ParseFloat(fmt.Sprintf("%v", value), 64)
+		if errWant != nil || errGot != nil {
+			return false
+		}
+
+		switch f.Operator {
+		case LESS:
+			return got < want
+		case LESSEQUAL:
+			return got <= want
+		case GREATER:
+			return got > want
+		case GREATEREQUAL:
+			return got >= want
+		case NOTEQUAL:
+			return got != want
+		default:
+			return got == want
+		}
+	}
+
+	got := fmt.Sprintf("%v", value)
+	switch f.Operator {
+	case NOTEQUAL:
+		return got != f.Value
+	case ARRAYCONTAINS:
+		return containsValue(value, f.Value)
+	default:
+		return got == f.Value
+	}
+}
+
+func containsValue(value interface{}, target string) bool {
+	for _, item := range toStringSlice(value) {
+		if item == target {
+			return true
+		}
+	}
+
+	return false
+}
+
+func sortDocs(docs []map[string]interface{}, orders []Order) {
+	sort.SliceStable(docs, func(i, j int) bool {
+		for _, o := range orders {
+			vi := fmt.Sprintf("%v", docs[i][o.Field])
+			vj := fmt.Sprintf("%v", docs[j][o.Field])
+			if vi == vj {
+				continue
+			}
+			if o.Descending {
+				return vi > vj
+			}
+			return vi < vj
+		}
+		return false
+	})
+}
@@ -0,0 +1,45 @@
+package work
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryRevocationStore is a RevocationStore that keeps revoked jtis in
+// process memory, pruning entries once they'd have expired anyway.
+type MemoryRevocationStore struct {
+	mu      sync.RWMutex
+	revoked map[string]time.Time
+}
+
+func NewMemoryRevocationStore() *MemoryRevocationStore {
+	return &MemoryRevocationStore{revoked: map[string]time.Time{}}
+}
+
+func (s *MemoryRevocationStore) Revoke(ctx context.Context, jti string, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.revoked[jti] = expiresAt
+	return nil
+}
+
+func (s *MemoryRevocationStore) IsRevoked(ctx context.Context, jti string) (bool, error) {
+	s.mu.RLock()
+	expiresAt, ok := s.revoked[jti]
+	s.mu.RUnlock()
+
+	if !ok {
+		return false, nil
+	}
+
+	if time.Now().After(expiresAt) {
+		s.mu.Lock()
+		delete(s.revoked, jti)
+		s.mu.Unlock()
+		return false, nil
+	}
+
+	return true, nil
+}
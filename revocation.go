@@ -0,0 +1,23 @@
+package work
+
+import (
+	"context"
+	"time"
+)
+
+// RevocationStore tracks revoked token jtis so Auth and RefreshToken can
+// reject a token before its exp (e.g. after logout or a ban). The
+// in-memory implementation is used by default; register another with
+// UseRevocationStore at startup, e.g. NewFirestoreRevocationStore().
+type RevocationStore interface {
+	Revoke(ctx context.Context, jti string, expiresAt time.Time) error
+	IsRevoked(ctx context.Context, jti string) (bool, error)
+}
+
+var revocation RevocationStore = NewMemoryRevocationStore()
+
+// UseRevocationStore registers the revocation store used by Auth and
+// RefreshToken. Call it once at startup, before serving any requests.
+func UseRevocationStore(s RevocationStore) {
+	revocation = s
+}
@@ -0,0 +1,127 @@
+package work
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SearchConfig controls how Tokenize expands a Model's Searchify() terms
+// into the tokens stored in Record.Raw and queried via Payload.Search.
+//
+// The previous approach generated every substring of every term - an
+// O(n^2) blow-up per term that produced huge Raw arrays and drove up
+// Firestore document size and write cost. Tokenize instead produces a
+// bounded set per term: the lowercased whole token, character n-grams in
+// [NGramMin, NGramMax] (trigrams by default), and - if MaxPrefix > 0 -
+// prefixes up to that length (useful for "starts with" search; disabled
+// by default since it roughly doubles the token count).
+type SearchConfig struct {
+	NGramMin  int
+	NGramMax  int
+	MaxPrefix int
+}
+
+// DefaultSearchConfig is the config StorageSync and StorageList use unless
+// overridden with SetSearchConfig. A Model wanting a different shape can
+// also just call Tokenize directly from its own Searchify() with a
+// SearchConfig of its choosing.
+var DefaultSearchConfig = SearchConfig{NGramMin: 3, NGramMax: 3}
+
+// SetSearchConfig overrides DefaultSearchConfig, e.g. to enable prefix
+// tokens or widen the n-gram range for every Model that doesn't tokenize
+// itself.
+func SetSearchConfig(cfg SearchConfig) {
+	DefaultSearchConfig = cfg
+}
+
+// Tokenize expands terms into the deduplicated set of tokens Firestore's
+// array-contains filter can match against.
+func Tokenize(terms []string, cfg SearchConfig) []string {
+	seen := map[string]struct{}{}
+	var tokens []string
+
+	add := func(token string) {
+		if len(token) == 0 {
+			return
+		}
+		if _, ok := seen[token]; ok {
+			return
+		}
+		seen[token] = struct{}{}
+		tokens = append(tokens, token)
+	}
+
+	min, max := cfg.NGramMin, cfg.NGramMax
+	if min <= 0 {
+		min = 3
+	}
+	if max < min {
+		max = min
+	}
+
+	for _, term := range terms {
+		term = strings.ToLower(strings.TrimSpace(term))
+		if len(term) == 0 {
+			continue
+		}
+
+		add(term)
+
+		runes := []rune(term)
+
+		for length := 1; length <= cfg.MaxPrefix && length < len(runes); length++ {
+			add(string(runes[:length]))
+		}
+
+		for length := min; length <= max && length <= len(runes); length++ {
+			for start := 0; start <= len(runes)-length; start++ {
+				add(string(runes[start : start+length]))
+			}
+		}
+	}
+
+	return tokens
+}
+
+// searchTokens tokenizes a Payload.Search query the same way Tokenize
+// indexes a Model, so its tokens can be matched against Record.Raw.
+func searchTokens(search string) []string {
+	return Tokenize(strings.Fields(search), DefaultSearchConfig)
+}
+
+// matchesAllTokens reports whether every token is present in a raw
+// document's Raw field. Firestore only supports a single array-contains
+// filter per query, so the Firestore backend pushes the first search token
+// down as a query filter and uses this to AND the remaining tokens
+// client-side; MemoryBackend uses it to check every token.
+func matchesAllTokens(raw []string, tokens []string) bool {
+	present := make(map[string]struct{}, len(raw))
+	for _, v := range raw {
+		present[v] = struct{}{}
+	}
+
+	for _, token := range tokens {
+		if _, ok := present[token]; !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// toStringSlice normalizes a document's Raw field, which may come back as
+// []string (MemoryBackend) or []interface{} (a JSON/Firestore decode).
+func toStringSlice(value interface{}) []string {
+	switch v := value.(type) {
+	case []string:
+		return v
+	case []interface{}:
+		out := make([]string, len(v))
+		for i, item := range v {
+			out[i] = fmt.Sprintf("%v", item)
+		}
+		return out
+	default:
+		return nil
+	}
+}
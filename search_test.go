@@ -0,0 +1,99 @@
+package work
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestTokenizeTrigrams(t *testing.T) {
+	tokens := Tokenize([]string{"Hello"}, SearchConfig{NGramMin: 3, NGramMax: 3})
+
+	want := []string{"hello", "hel", "ell", "llo"}
+	sort.Strings(tokens)
+	sort.Strings(want)
+
+	if !reflect.DeepEqual(tokens, want) {
+		t.Fatalf("Tokenize(%q) = %v, want %v", "Hello", tokens, want)
+	}
+}
+
+func TestTokenizeDedup(t *testing.T) {
+	tokens := Tokenize([]string{"aaaa"}, SearchConfig{NGramMin: 3, NGramMax: 3})
+
+	seen := map[string]int{}
+	for _, tok := range tokens {
+		seen[tok]++
+	}
+
+	for tok, n := range seen {
+		if n > 1 {
+			t.Fatalf("Tokenize produced duplicate token %q", tok)
+		}
+	}
+}
+
+func TestTokenizePrefix(t *testing.T) {
+	tokens := Tokenize([]string{"hello"}, SearchConfig{NGramMin: 3, NGramMax: 3, MaxPrefix: 3})
+
+	for _, want := range []string{"h", "he", "hel"} {
+		found := false
+		for _, tok := range tokens {
+			if tok == want {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Fatalf("Tokenize with MaxPrefix=3 missing prefix token %q, got %v", want, tokens)
+		}
+	}
+}
+
+func TestTokenizeBlankNGramRangeDefaultsToTrigrams(t *testing.T) {
+	got := Tokenize([]string{"hello"}, SearchConfig{})
+	want := Tokenize([]string{"hello"}, SearchConfig{NGramMin: 3, NGramMax: 3})
+
+	sort.Strings(got)
+	sort.Strings(want)
+
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Tokenize with zero-value SearchConfig = %v, want %v", got, want)
+	}
+}
+
+func TestTokenizeSkipsBlankTerms(t *testing.T) {
+	tokens := Tokenize([]string{"", "  ", "hi"}, SearchConfig{NGramMin: 3, NGramMax: 3})
+
+	if len(tokens) != 1 || tokens[0] != "hi" {
+		t.Fatalf("Tokenize with blank terms = %v, want [hi]", tokens)
+	}
+}
+
+func TestMatchesAllTokens(t *testing.T) {
+	raw := []string{"hel", "ell", "llo"}
+
+	if !matchesAllTokens(raw, []string{"hel", "llo"}) {
+		t.Fatal("matchesAllTokens should match when all tokens are present")
+	}
+	if matchesAllTokens(raw, []string{"hel", "xyz"}) {
+		t.Fatal("matchesAllTokens should not match when a token is missing")
+	}
+	if !matchesAllTokens(raw, nil) {
+		t.Fatal("matchesAllTokens with no tokens should always match")
+	}
+}
+
+func TestToStringSlice(t *testing.T) {
+	if got := toStringSlice([]string{"a", "b"}); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Fatalf("toStringSlice([]string) = %v", got)
+	}
+
+	if got := toStringSlice([]interface{}{"a", "b"}); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Fatalf("toStringSlice([]interface{}) = %v", got)
+	}
+
+	if got := toStringSlice(nil); got != nil {
+		t.Fatalf("toStringSlice(nil) = %v, want nil", got)
+	}
+}
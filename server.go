@@ -2,15 +2,19 @@ package work
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io/ioutil"
 	"net/http"
 	"os"
+	"os/signal"
 	"reflect"
 	"regexp"
 	"strings"
+	"sync"
+	"syscall"
 	"time"
 
 	"github.com/golang-jwt/jwt"
@@ -39,61 +43,132 @@ const (
 	ROLE     = "Role"
 	USER     = "Username"
 	PASSWORD = "Password"
+	JTI      = "jti"
+	TYP      = "typ"
+)
+
+const (
+	accessTokenType  = "access"
+	refreshTokenType = "refresh"
 )
 
 type ctxKey struct{}
 
 type Route struct {
-	method  Method
-	regex   *regexp.Regexp
-	handler http.HandlerFunc
+	method      Method
+	regex       *regexp.Regexp
+	handler     http.HandlerFunc
+	middlewares []Middleware
 }
 
 type serverConfig struct {
-	allowedHosts []string
-	secretKey    []byte
+	allowedHosts   []string
+	secretKey      []byte
+	accessTTL      time.Duration
+	refreshTTL     time.Duration
+	requestTimeout time.Duration
 }
 
 var server serverConfig
 
-func NewServer(routes []Route) error {
-	accessFile, err := os.Open("./config.json")
-	if err != nil {
-		return err
+// allowedHostsMu guards server.allowedHosts, the one serverConfig field
+// WatchConfig hot-reloads while request goroutines are reading it (other
+// fields are set once in NewServer and require a restart to change).
+var allowedHostsMu sync.RWMutex
+
+func setAllowedHosts(hosts []string) {
+	allowedHostsMu.Lock()
+	defer allowedHostsMu.Unlock()
+	server.allowedHosts = hosts
+}
+
+func allowedHosts() []string {
+	allowedHostsMu.RLock()
+	defer allowedHostsMu.RUnlock()
+	return server.allowedHosts
+}
+
+// NewServer starts the HTTP server and blocks until ctx is cancelled or a
+// SIGINT/SIGTERM is received, at which point it drains in-flight requests
+// via server.Shutdown and closes the Firestore client before returning.
+func NewServer(ctx context.Context, cfg *Config, routes []Route) error {
+	server.secretKey = []byte(cfg.SecretKey)
+	setAllowedHosts(cfg.AllowedHosts)
+	server.accessTTL = time.Duration(cfg.AccessTTLMinutes * float64(time.Minute))
+	server.refreshTTL = time.Duration(cfg.RefreshTTLHours * float64(time.Hour))
+	server.requestTimeout = time.Duration(cfg.RequestTimeoutSeconds * float64(time.Second))
+
+	httpServer := &http.Server{
+		Addr:         fmt.Sprintf(":%d", cfg.Port),
+		Handler:      http.HandlerFunc(makeHandler(routes)),
+		ReadTimeout:  time.Duration(cfg.ReadTimeoutSeconds * float64(time.Second)),
+		WriteTimeout: time.Duration(cfg.WriteTimeoutSeconds * float64(time.Second)),
+		IdleTimeout:  time.Duration(cfg.IdleTimeoutSeconds * float64(time.Second)),
 	}
-	defer accessFile.Close()
 
-	jsonFile, err := ioutil.ReadAll(accessFile)
-	if err != nil {
+	shutdownCtx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	errCh := make(chan error, 1)
+	go func() {
+		if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			errCh <- err
+			return
+		}
+		errCh <- nil
+	}()
+
+	select {
+	case err := <-errCh:
 		return err
-	}
+	case <-shutdownCtx.Done():
+		logger.Info("server shutting down")
 
-	var result map[string]interface{}
-	json.Unmarshal([]byte(jsonFile), &result)
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
 
-	server.secretKey = []byte(result["server_secret_key"].(string))
-	server.allowedHosts = strings.Split(result["server_allowed_hosts"].(string), ",")
-	port := int(result["server_port"].(float64))
+		if err := httpServer.Shutdown(shutdownCtx); err != nil {
+			logger.Error("server shutdown failed", "error", err)
+		}
 
-	if len(os.Getenv("API_WORK")) > 0 {
-		server.allowedHosts = append(server.allowedHosts, "http://localhost:8082")
-	}
+		FireStoreClose()
 
-	listenAddr := fmt.Sprintf(":%d", port)
-	handler := http.HandlerFunc(makeHandler(routes))
-	return http.ListenAndServe(listenAddr, handler)
+		return <-errCh
+	}
 }
 
 func NewRoute(method Method, pattern string, handler http.HandlerFunc) Route {
-	return Route{method, regexp.MustCompile("^" + pattern + "$"), handler}
+	return Route{method, regexp.MustCompile("^" + pattern + "$"), handler, nil}
+}
+
+// NewSecureRoute is NewRoute plus a required-roles list enforced by the
+// built-in RequireRoles middleware, e.g.:
+//
+//	work.NewSecureRoute(work.GET, pattern, []string{"admin"}, handler)
+func NewSecureRoute(method Method, pattern string, roles []string, handler http.HandlerFunc) Route {
+	return Route{method, regexp.MustCompile("^" + pattern + "$"), handler, []Middleware{RequireRoles(roles...)}}
+}
+
+// statusRecorder wraps a ResponseWriter so the logging middleware below can
+// see the status code a handler wrote and the username dispatch resolved,
+// without re-running Auth.
+type statusRecorder struct {
+	http.ResponseWriter
+	status   int
+	username string
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
 }
 
 func makeHandler(routes []Route) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
+	dispatch := func(w http.ResponseWriter, r *http.Request) {
 		var allow []string
 
 		origin := r.Header.Get("Origin")
-		if !contains(server.allowedHosts, origin) {
+		if !contains(allowedHosts(), origin) {
 			Respond(w, http.StatusForbidden, EMPTY, nil)
 			return
 		} else {
@@ -133,8 +208,24 @@ func makeHandler(routes []Route) http.HandlerFunc {
 					continue
 				}
 
+				// Resolve the caller once here, rather than once in
+				// RequireRoles and again in the logging wrapper below -
+				// both reuse this via CurrentUser/rec.username instead of
+				// re-parsing the token and re-hitting the revocation store.
+				username, role, authErr := Auth(r)
+
 				ctx := context.WithValue(r.Context(), ctxKey{}, matches[1:])
-				route.handler(w, r.WithContext(ctx))
+				if authErr == nil {
+					ctx = context.WithValue(ctx, userCtxKey{}, username)
+					ctx = context.WithValue(ctx, roleCtxKey{}, role)
+				}
+
+				if rec, ok := w.(*statusRecorder); ok {
+					rec.username = username
+				}
+
+				handler := chain(globalMiddleware, chain(route.middlewares, route.handler))
+				handler(w, r.WithContext(ctx))
 				return
 			}
 		}
@@ -148,6 +239,27 @@ func makeHandler(routes []Route) http.HandlerFunc {
 
 		http.NotFound(w, r)
 	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		if server.requestTimeout > 0 {
+			ctx, cancel := context.WithTimeout(r.Context(), server.requestTimeout)
+			defer cancel()
+			r = r.WithContext(ctx)
+		}
+
+		dispatch(rec, r)
+
+		logger.Info("request",
+			"method", r.Method,
+			"path", r.URL.Path,
+			"status", rec.status,
+			"latency", time.Since(start),
+			"username", rec.username,
+		)
+	}
 }
 
 // ////////////////////////////////////////////////////////////////////////////
@@ -201,50 +313,163 @@ func ReadQueryParam(r *http.Request, index int) string {
 
 // ////////////////////////////////////////////////////////////////////////////
 // JWT
+//
+// Autorize issues a short-lived access token plus a longer-lived refresh
+// token, each carrying its own jti so it can be individually revoked (see
+// revocation.go) without rotating the shared secret. RefreshToken exchanges
+// a still-valid refresh token for a new pair, rotating the old one so it
+// can't be reused.
+
+// Autorize issues a paired access + refresh token for username/role, with
+// TTLs configured via server_access_ttl_minutes/server_refresh_ttl_hours
+// (defaulting to 24h/30d).
+func Autorize(username string, role string) (access string, refresh string, err error) {
+	access, err = newToken(username, role, accessTokenType, server.accessTTL)
+	if err != nil {
+		return "", "", err
+	}
 
-func Autorize(username string, role string) (string, error) {
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		USER:  username,
-		ROLE:  role,
-		"exp": time.Now().Add(24 * time.Hour).Unix(),
-	})
+	refresh, err = newToken(username, role, refreshTokenType, server.refreshTTL)
+	if err != nil {
+		return "", "", err
+	}
+
+	return access, refresh, nil
+}
+
+// RefreshToken exchanges a still-valid, unrevoked refresh token for a new
+// access + refresh pair, revoking the old refresh token so it can't be
+// used again. ctx bounds the revocation store round-trips.
+func RefreshToken(ctx context.Context, refresh string) (access string, newRefresh string, err error) {
+	claims, err := parseToken(refresh)
+	if err != nil {
+		return "", "", err
+	}
 
-	tokenString, err := token.SignedString(server.secretKey)
+	if claims[TYP] != refreshTokenType {
+		return "", "", errors.New("not a refresh token")
+	}
+
+	revoked, err := isRevoked(ctx, claims)
 	if err != nil {
-		return "Signing Error", err
+		return "", "", err
+	}
+	if revoked {
+		return "", "", errors.New("refresh token revoked")
 	}
 
-	return tokenString, nil
+	if err := revokeClaims(ctx, claims); err != nil {
+		return "", "", err
+	}
+
+	return Autorize(claims[USER].(string), claims[ROLE].(string))
+}
+
+// Revoke invalidates a still-unexpired access or refresh token, e.g. on
+// logout or a ban, without waiting for its exp. ctx bounds the revocation
+// store round-trip.
+func Revoke(ctx context.Context, token string) error {
+	claims, err := parseToken(token)
+	if err != nil {
+		return err
+	}
+
+	return revokeClaims(ctx, claims)
 }
 
 func Auth(r *http.Request) (string, string, error) {
 	if r.Header["Authorization"] != nil && len(r.Header["Authorization"]) == 1 && strings.Contains(r.Header["Authorization"][0], "Bearer ") {
 		bearer := strings.Split(r.Header["Authorization"][0], " ")[1]
-		token, err := jwt.Parse(bearer, func(token *jwt.Token) (interface{}, error) {
-			// Don't forget to validate the alg is what you expect:
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-			}
-			return server.secretKey, nil
-		})
-
+		claims, err := parseToken(bearer)
 		if err != nil {
 			return "", "", err
 		}
 
-		if token.Valid {
-			claims, ok := token.Claims.(jwt.MapClaims)
-			if ok {
-				username := claims[USER].(string)
-				role := claims[ROLE].(string)
-				return username, role, nil
-			}
+		if claims[TYP] != accessTokenType {
+			return "", "", errors.New("not an access token")
 		}
+
+		revoked, err := isRevoked(r.Context(), claims)
+		if err != nil {
+			return "", "", err
+		}
+		if revoked {
+			return "", "", errors.New("token revoked")
+		}
+
+		username := claims[USER].(string)
+		role := claims[ROLE].(string)
+		return username, role, nil
 	}
 
 	return "", "", errors.New("no token/error parsing token")
 }
 
+func newToken(username string, role string, typ string, ttl time.Duration) (string, error) {
+	jti, err := newJTI()
+	if err != nil {
+		return "", err
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		USER:  username,
+		ROLE:  role,
+		TYP:   typ,
+		JTI:   jti,
+		"exp": time.Now().Add(ttl).Unix(),
+	})
+
+	return token.SignedString(server.secretKey)
+}
+
+func parseToken(bearer string) (jwt.MapClaims, error) {
+	token, err := jwt.Parse(bearer, func(token *jwt.Token) (interface{}, error) {
+		// Don't forget to validate the alg is what you expect:
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return server.secretKey, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !token.Valid || !ok {
+		return nil, errors.New("no token/error parsing token")
+	}
+
+	return claims, nil
+}
+
+func isRevoked(ctx context.Context, claims jwt.MapClaims) (bool, error) {
+	jti, ok := claims[JTI].(string)
+	if !ok {
+		return false, nil
+	}
+
+	return revocation.IsRevoked(ctx, jti)
+}
+
+func revokeClaims(ctx context.Context, claims jwt.MapClaims) error {
+	jti, ok := claims[JTI].(string)
+	if !ok {
+		return nil
+	}
+
+	exp, _ := claims["exp"].(float64)
+	return revocation.Revoke(ctx, jti, time.Unix(int64(exp), 0))
+}
+
+func newJTI() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(buf), nil
+}
+
 func contains(s []string, str string) bool {
 	for _, v := range s {
 		if v == str {
@@ -0,0 +1,194 @@
+package work
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func setupServerAuth(t *testing.T) {
+	t.Helper()
+	server.secretKey = []byte("test-secret")
+	server.accessTTL = time.Hour
+	server.refreshTTL = 24 * time.Hour
+	UseRevocationStore(NewMemoryRevocationStore())
+}
+
+func TestAutorizeAndAuth(t *testing.T) {
+	setupServerAuth(t)
+
+	access, _, err := Autorize("alice", "admin")
+	if err != nil {
+		t.Fatalf("Autorize: %v", err)
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Authorization", "Bearer "+access)
+
+	username, role, err := Auth(r)
+	if err != nil {
+		t.Fatalf("Auth: %v", err)
+	}
+	if username != "alice" || role != "admin" {
+		t.Fatalf("Auth = (%q, %q), want (alice, admin)", username, role)
+	}
+}
+
+func TestAuthRejectsRevokedToken(t *testing.T) {
+	setupServerAuth(t)
+
+	access, _, err := Autorize("alice", "admin")
+	if err != nil {
+		t.Fatalf("Autorize: %v", err)
+	}
+
+	if err := Revoke(context.Background(), access); err != nil {
+		t.Fatalf("Revoke: %v", err)
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Authorization", "Bearer "+access)
+
+	if _, _, err := Auth(r); err == nil {
+		t.Fatal("Auth should reject a revoked access token")
+	}
+}
+
+func TestRefreshTokenRotatesAndRejectsReplay(t *testing.T) {
+	setupServerAuth(t)
+
+	_, refresh, err := Autorize("alice", "admin")
+	if err != nil {
+		t.Fatalf("Autorize: %v", err)
+	}
+
+	access, newRefresh, err := RefreshToken(context.Background(), refresh)
+	if err != nil {
+		t.Fatalf("RefreshToken: %v", err)
+	}
+	if access == "" || newRefresh == "" {
+		t.Fatal("RefreshToken returned an empty token")
+	}
+	if newRefresh == refresh {
+		t.Fatal("RefreshToken should rotate the refresh token")
+	}
+
+	if _, _, err := RefreshToken(context.Background(), refresh); err == nil {
+		t.Fatal("RefreshToken should reject a replayed (already-rotated) refresh token")
+	}
+}
+
+func TestRefreshTokenRejectsAccessToken(t *testing.T) {
+	setupServerAuth(t)
+
+	access, _, err := Autorize("alice", "admin")
+	if err != nil {
+		t.Fatalf("Autorize: %v", err)
+	}
+
+	if _, _, err := RefreshToken(context.Background(), access); err == nil {
+		t.Fatal("RefreshToken should reject a token that isn't typed as a refresh token")
+	}
+}
+
+func TestMemoryRevocationStorePrunesExpired(t *testing.T) {
+	s := NewMemoryRevocationStore()
+	ctx := context.Background()
+
+	if err := s.Revoke(ctx, "jti-1", time.Now().Add(-time.Minute)); err != nil {
+		t.Fatalf("Revoke: %v", err)
+	}
+
+	revoked, err := s.IsRevoked(ctx, "jti-1")
+	if err != nil {
+		t.Fatalf("IsRevoked: %v", err)
+	}
+	if revoked {
+		t.Fatal("IsRevoked should prune and report false for an already-expired revocation")
+	}
+}
+
+func TestMemoryRevocationStoreStillRevoked(t *testing.T) {
+	s := NewMemoryRevocationStore()
+	ctx := context.Background()
+
+	if err := s.Revoke(ctx, "jti-1", time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("Revoke: %v", err)
+	}
+
+	revoked, err := s.IsRevoked(ctx, "jti-1")
+	if err != nil {
+		t.Fatalf("IsRevoked: %v", err)
+	}
+	if !revoked {
+		t.Fatal("IsRevoked should report true for a not-yet-expired revocation")
+	}
+}
+
+func TestRequireRolesUnauthorized(t *testing.T) {
+	setupServerAuth(t)
+
+	handler := RequireRoles("admin")(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("handler should not run without a valid token")
+	})
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, r)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestRequireRolesForbidden(t *testing.T) {
+	setupServerAuth(t)
+
+	access, _, err := Autorize("alice", "member")
+	if err != nil {
+		t.Fatalf("Autorize: %v", err)
+	}
+
+	handler := RequireRoles("admin")(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("handler should not run for the wrong role")
+	})
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Authorization", "Bearer "+access)
+	w := httptest.NewRecorder()
+	handler(w, r)
+
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusForbidden)
+	}
+}
+
+func TestRequireRolesAllows(t *testing.T) {
+	setupServerAuth(t)
+
+	access, _, err := Autorize("alice", "admin")
+	if err != nil {
+		t.Fatalf("Autorize: %v", err)
+	}
+
+	var gotUser, gotRole string
+	handler := RequireRoles("admin")(func(w http.ResponseWriter, r *http.Request) {
+		gotUser = CurrentUser(r)
+		gotRole = CurrentRole(r)
+		w.WriteHeader(http.StatusOK)
+	})
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Authorization", "Bearer "+access)
+	w := httptest.NewRecorder()
+	handler(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if gotUser != "alice" || gotRole != "admin" {
+		t.Fatalf("CurrentUser/CurrentRole = (%q, %q), want (alice, admin)", gotUser, gotRole)
+	}
+}
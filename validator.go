@@ -4,17 +4,20 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io/ioutil"
-	"log"
 	"os"
 	"strings"
+	"sync"
 
 	"github.com/go-playground/validator/v10"
 )
 
 // use a single instance of Validate, it caches struct info
 var validate *validator.Validate
-var translations map[string]map[string]string
+
+var (
+	translationsMu sync.RWMutex
+	translations   map[string]map[string]string
+)
 
 const lang = "pt" // TODO - config
 
@@ -27,41 +30,48 @@ type validateInterface interface {
 	Validate() []Error
 }
 
-func NewValidator() {
+func NewValidator(cfg *Config) error {
 	validate = validator.New()
-
-	err := registerTranslations()
-	if err != nil {
-		panic(err)
-	}
+	return loadTranslations(cfg.TranslationsPath)
 }
 
-func registerTranslations() error {
-	configFile, _ := os.Open("./config.json") // TODO - constant or something
-	defer configFile.Close()
-
-	jsonFile, err := ioutil.ReadAll(configFile)
+// loadTranslations reads path into the package translations map. It's
+// also what WatchConfig calls to hot-reload translations without
+// restarting the server, so access to translations is guarded by
+// translationsMu.
+func loadTranslations(path string) error {
+	jsonFile, err := os.ReadFile(path)
 	if err != nil {
 		return err
 	}
 
-	json.Unmarshal([]byte(jsonFile), &translations)
+	var loaded map[string]map[string]string
+	if err := json.Unmarshal(jsonFile, &loaded); err != nil {
+		return fmt.Errorf("work: malformed translations %s: %w", path, err)
+	}
+
+	translationsMu.Lock()
+	translations = loaded
+	translationsMu.Unlock()
 
 	return nil
 }
 
-func fieldTranslation(fe validator.FieldError) string {
-	var match string
+func translation(language string, key string) string {
+	translationsMu.RLock()
+	defer translationsMu.RUnlock()
+
+	return translations[language][key]
+}
 
+func fieldTranslation(fe validator.FieldError) string {
 	if len(fe.Namespace()) > 0 {
-		match := translations[lang][fe.Namespace()]
-		if len(match) > 0 {
+		if match := translation(lang, fe.Namespace()); len(match) > 0 {
 			return match
 		}
 	}
 
-	match = translations[lang][fe.Field()]
-	if len(match) > 0 {
+	if match := translation(lang, fe.Field()); len(match) > 0 {
 		return match
 	}
 
@@ -77,11 +87,12 @@ func truncatedSprintf(str string, args ...interface{}) (string, error) {
 }
 
 func errorTranslation(fe validator.FieldError) string {
-	match := translations[lang][fe.Tag()]
+	match := translation(lang, fe.Tag())
 	if len(match) > 0 {
 		result, err := truncatedSprintf(match, fieldTranslation(fe), fe.Param())
 		if err != nil {
-			log.Fatal(err)
+			logger.Warn("translation format failed, falling back to default error", "tag", fe.Tag(), "error", err)
+			return fe.Error()
 		}
 
 		return result